@@ -0,0 +1,48 @@
+// Package articuno implements Articuno's CLI: a long-running "serve"
+// subcommand (the original behavior), a one-shot "query" lookup, and a
+// "warm" command to bulk pre-populate the cache.
+package articuno
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Run dispatches args (typically os.Args[1:]) to the matching subcommand and
+// returns the process exit code. With no subcommand, or one starting with
+// "-", it runs "serve" so `articuno --port 9090` keeps working the way the
+// flagless binary used to.
+func Run(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runServe(args)
+	}
+
+	switch args[0] {
+	case "serve":
+		return runServe(args[1:])
+	case "query":
+		return runQuery(args[1:])
+	case "warm":
+		return runWarm(args[1:])
+	case "help":
+		printUsage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "articuno: unknown command %q\n\n", args[0])
+		printUsage()
+		return 1
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, `Usage: articuno <command> [flags]
+
+Commands:
+  serve            Run the weather API server (default)
+  query <city>     Look up a city once and print the result to stdout
+  warm <file>      Pre-populate the cache from a newline-delimited city list
+
+Flags mirror environment variables with the same name, e.g. --port falls
+back to PORT. Run "articuno <command> -h" for a command's flags.`)
+}
@@ -0,0 +1,90 @@
+package articuno
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/JesterSe7en/Articuno/internal/app"
+	"github.com/JesterSe7en/Articuno/internal/weather"
+)
+
+// redisConfigured reports whether a Redis backend is reachable via flags or
+// environment variables, without actually dialing it.
+func redisConfigured(redisAddr string) bool {
+	return redisAddr != "" || os.Getenv("REDIS_ADDR") != "" || os.Getenv("REDIS_ADDRS") != "" || os.Getenv("REDIS_URL") != ""
+}
+
+// runQuery looks up a single city, printing the result to stdout and
+// returning 1 on any failure.
+func runQuery(args []string) int {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	redisAddr := fs.String("redis-addr", os.Getenv("REDIS_ADDR"), "Redis address (host:port); falls back to REDIS_ADDR, REDIS_ADDRS, or REDIS_URL")
+	apiKey := fs.String("weather-api-key", os.Getenv("WEATHER_API_KEY"), "VisualCrossing API key; falls back to WEATHER_API_KEY")
+	format := fs.String("format", "json", "output format: json or table")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: articuno query [flags] <city>")
+		return 1
+	}
+	city := fs.Arg(0)
+
+	if *apiKey == "" {
+		fmt.Fprintln(os.Stderr, "Please set the WEATHER_API_KEY environment variable or pass --weather-api-key")
+		return 1
+	}
+
+	ctx := context.Background()
+	chain := getProviderChain(*apiKey)
+
+	var reportJSON string
+	if redisConfigured(*redisAddr) {
+		rdb, err := getRedisConnection(*redisAddr)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Cannot connect to cache:", err)
+			return 1
+		}
+		a := app.NewApp(rdb, chain, envDuration("CACHE_FRESH_TTL", defaultFreshTTL), envDuration("CACHE_STALE_TTL", defaultStaleTTL), envInt("CACHE_JITTER_PCT", defaultJitterPct))
+		reportJSON, err = a.GetWeatherData(ctx, city, "")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Query failed:", err)
+			return 1
+		}
+	} else {
+		report, err := chain.Fetch(ctx, city, "")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Query failed:", err)
+			return 1
+		}
+		raw, err := json.Marshal(report)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Query failed:", err)
+			return 1
+		}
+		reportJSON = string(raw)
+	}
+
+	switch *format {
+	case "table":
+		var report weather.Report
+		if err := json.Unmarshal([]byte(reportJSON), &report); err != nil {
+			fmt.Fprintln(os.Stderr, "Query failed:", err)
+			return 1
+		}
+		printReportTable(report)
+	default:
+		fmt.Println(reportJSON)
+	}
+
+	return 0
+}
+
+func printReportTable(report weather.Report) {
+	fmt.Printf("%-12s %s\n", "City", report.City)
+	fmt.Printf("%-12s %.1f°C\n", "Temperature", report.TemperatureC)
+	fmt.Printf("%-12s %s\n", "Conditions", report.Conditions)
+	fmt.Printf("%-12s %s\n", "Provider", report.Provider)
+}
@@ -0,0 +1,80 @@
+package articuno
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/JesterSe7en/Articuno/internal/app"
+	"github.com/JesterSe7en/Articuno/internal/metrics"
+)
+
+// runServe runs the weather API server until it receives SIGINT/SIGTERM,
+// then shuts it down gracefully.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	redisAddr := fs.String("redis-addr", os.Getenv("REDIS_ADDR"), "Redis address (host:port); falls back to REDIS_ADDR, REDIS_ADDRS, or REDIS_URL")
+	apiKey := fs.String("weather-api-key", os.Getenv("WEATHER_API_KEY"), "VisualCrossing API key; falls back to WEATHER_API_KEY")
+	port := fs.Int("port", envInt("PORT", 8080), "port to listen on; falls back to PORT")
+	logFormat := fs.String("log-format", envString("LOG_FORMAT", "json"), "access log format: text or json; falls back to LOG_FORMAT")
+	fs.Parse(args)
+
+	if *apiKey == "" {
+		log.Println("Please set the WEATHER_API_KEY environment variable or pass --weather-api-key")
+		return 1
+	}
+
+	if err := metrics.SetLogFormat(*logFormat); err != nil {
+		log.Println(err)
+		return 1
+	}
+
+	rdb, err := getRedisConnection(*redisAddr)
+	if err != nil {
+		log.Println("Cannot connect to cache:", err)
+		return 1
+	}
+
+	chain := getProviderChain(*apiKey)
+	a := app.NewApp(rdb, chain, envDuration("CACHE_FRESH_TTL", defaultFreshTTL), envDuration("CACHE_STALE_TTL", defaultStaleTTL), envInt("CACHE_JITTER_PCT", defaultJitterPct))
+
+	subscriberCtx, stopSubscriber := context.WithCancel(context.Background())
+	defer stopSubscriber()
+	go a.RunInvalidationSubscriber(subscriberCtx)
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", *port),
+		Handler: a.Handler(),
+	}
+	go func() {
+		log.Printf("Starting server on localhost%s\n", server.Addr)
+		// http.ListenAndServe() returns ErrServerClosed on graceful shutdown, not nil
+		// https://dev.to/mokiat/proper-http-shutdown-in-go-3fji
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Println("Cannot start web server:", err)
+		}
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM) // respond to SIGINT(ctrl+c) and SIGTERM (system asks the program to terminate gracefully)
+	<-sigChan                                               // block until a signal is received
+
+	stopSubscriber()
+
+	shutdownCtx, shutdownRelease := context.WithTimeout(context.Background(), 10*time.Second) // 10 seconds wait for graceful shutdown
+	defer shutdownRelease()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("HTTP shutdown error:", err)
+		return 1
+	}
+	log.Println("Graceful shutdown complete.")
+	return 0
+}
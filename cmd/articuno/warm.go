@@ -0,0 +1,166 @@
+package articuno
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JesterSe7en/Articuno/internal/app"
+	"github.com/JesterSe7en/Articuno/internal/cache"
+	"github.com/JesterSe7en/Articuno/internal/weather"
+)
+
+const defaultWarmWorkers = 8
+
+// runWarm reads a newline-delimited list of cities from a file and
+// pre-populates the cache for each, fetching from the provider chain across
+// a bounded pool of workers and writing the results back in one pipelined
+// Redis SET.
+func runWarm(args []string) int {
+	fs := flag.NewFlagSet("warm", flag.ExitOnError)
+	redisAddr := fs.String("redis-addr", os.Getenv("REDIS_ADDR"), "Redis address (host:port); falls back to REDIS_ADDR, REDIS_ADDRS, or REDIS_URL")
+	apiKey := fs.String("weather-api-key", os.Getenv("WEATHER_API_KEY"), "VisualCrossing API key; falls back to WEATHER_API_KEY")
+	workers := fs.Int("workers", defaultWarmWorkers, "number of cities to fetch concurrently")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: articuno warm [flags] <file>")
+		return 1
+	}
+
+	if *apiKey == "" {
+		fmt.Fprintln(os.Stderr, "Please set the WEATHER_API_KEY environment variable or pass --weather-api-key")
+		return 1
+	}
+
+	cities, err := readCities(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Cannot read city list:", err)
+		return 1
+	}
+	if len(cities) == 0 {
+		fmt.Fprintln(os.Stderr, "No cities found in", fs.Arg(0))
+		return 1
+	}
+
+	rdb, err := getRedisConnection(*redisAddr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Cannot connect to cache:", err)
+		return 1
+	}
+	chain := getProviderChain(*apiKey)
+	staleTTL := envDuration("CACHE_STALE_TTL", defaultStaleTTL)
+
+	ctx := context.Background()
+	results := warmAll(ctx, chain, cities, *workers, staleTTL)
+
+	entries := make(map[string]string)
+	failed := 0
+	for _, res := range results {
+		if res.err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", res.city, res.err)
+			failed++
+			continue
+		}
+		entries[cache.BuildKey(res.city)] = res.envelopeJSON
+	}
+
+	if len(entries) > 0 {
+		if err := rdb.SetMany(ctx, entries, staleTTL); err != nil {
+			fmt.Fprintln(os.Stderr, "Cannot write cache:", err)
+			return 1
+		}
+	}
+
+	fmt.Printf("Warmed %d of %d cities\n", len(entries), len(cities))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+type warmResult struct {
+	city         string
+	envelopeJSON string
+	err          error
+}
+
+// warmAll fetches cities from chain across a bounded pool of workers,
+// returning one result per city in no particular order.
+func warmAll(ctx context.Context, chain *weather.ProviderChain, cities []string, workers int, staleTTL time.Duration) []warmResult {
+	jobs := make(chan string)
+	results := make(chan warmResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for city := range jobs {
+				results <- fetchForWarm(ctx, chain, city, staleTTL)
+			}
+		}()
+	}
+	go func() {
+		for _, city := range cities {
+			jobs <- city
+		}
+		close(jobs)
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	all := make([]warmResult, 0, len(cities))
+	for res := range results {
+		all = append(all, res)
+	}
+	return all
+}
+
+func fetchForWarm(ctx context.Context, chain *weather.ProviderChain, city string, staleTTL time.Duration) warmResult {
+	report, err := chain.Fetch(ctx, city, "")
+	if err != nil {
+		return warmResult{city: city, err: err}
+	}
+
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return warmResult{city: city, err: err}
+	}
+
+	envJSON, err := json.Marshal(app.CacheEnvelope{
+		FetchedAt: time.Now().Unix(),
+		TTL:       int64(staleTTL.Seconds()),
+		Payload:   reportJSON,
+	})
+	if err != nil {
+		return warmResult{city: city, err: err}
+	}
+
+	return warmResult{city: city, envelopeJSON: string(envJSON)}
+}
+
+func readCities(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cities []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if city := strings.TrimSpace(scanner.Text()); city != "" {
+			cities = append(cities, city)
+		}
+	}
+	return cities, scanner.Err()
+}
@@ -0,0 +1,119 @@
+package articuno
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JesterSe7en/Articuno/internal/cache"
+	"github.com/JesterSe7en/Articuno/internal/weather"
+)
+
+var defaultRedisPort = 6379
+
+const (
+	defaultProviderTimeout = 5 * time.Second
+	defaultHedgeDelay      = 300 * time.Millisecond
+
+	defaultFreshTTL  = 5 * time.Minute
+	defaultStaleTTL  = time.Hour
+	defaultJitterPct = 10
+)
+
+// getRedisConnection builds a cache.Cache from redisAddr (as given by
+// --redis-addr, if any) and the environment. REDIS_MODE selects the
+// topology (single, sentinel, cluster) and REDIS_ADDRS carries the
+// comma-separated address list for that topology; when neither redisAddr
+// nor REDIS_ADDRS is set it falls back to the legacy single-node
+// REDIS_URL/REDIS_PASSWORD pair so existing deployments keep working
+// unchanged.
+func getRedisConnection(redisAddr string) (cache.Cache, error) {
+	redisPassword := os.Getenv("REDIS_PASSWORD")
+
+	addrs := []string{}
+	switch {
+	case redisAddr != "":
+		addrs = append(addrs, redisAddr)
+	case os.Getenv("REDIS_ADDRS") != "":
+		for _, addr := range strings.Split(os.Getenv("REDIS_ADDRS"), ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addrs = append(addrs, addr)
+			}
+		}
+	case os.Getenv("REDIS_URL") != "":
+		addrs = append(addrs, fmt.Sprintf("%s:%d", os.Getenv("REDIS_URL"), defaultRedisPort))
+	}
+
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("please set --redis-addr, REDIS_ADDRS, or REDIS_URL and REDIS_PASSWORD")
+	}
+
+	cfg := cache.Config{
+		Mode:       cache.Mode(os.Getenv("REDIS_MODE")),
+		Addrs:      addrs,
+		MasterName: os.Getenv("REDIS_MASTER_NAME"),
+		Password:   redisPassword,
+	}
+
+	c, err := cache.NewCache(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+
+	return c, nil
+}
+
+// getProviderChain builds the chain of weather providers to fan out to.
+// VisualCrossing (the original backend) always leads the chain; the other
+// providers join in when their API keys are configured, or unconditionally
+// when they need none.
+func getProviderChain(visualCrossingAPIKey string) *weather.ProviderChain {
+	providers := []weather.Provider{weather.NewVisualCrossingProvider(visualCrossingAPIKey)}
+
+	if owmKey := os.Getenv("OPENWEATHERMAP_API_KEY"); owmKey != "" {
+		providers = append(providers, weather.NewOpenWeatherMapProvider(owmKey))
+	}
+	providers = append(providers, weather.NewOpenMeteoProvider())
+
+	return weather.NewProviderChain(providers, defaultProviderTimeout, defaultHedgeDelay)
+}
+
+// envString returns the value of name, or def if it's unset.
+func envString(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// envInt parses name as an int, falling back to def if it's unset or invalid.
+func envInt(name string, def int) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	i, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("invalid %s %q, using default %d: %v\n", name, raw, def, err)
+		return def
+	}
+	return i
+}
+
+// envDuration parses name as a time.Duration (e.g. "5m"), falling back to
+// def if it's unset or invalid.
+func envDuration(name string, def time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		log.Printf("invalid %s %q, using default %s: %v\n", name, raw, def, err)
+		return def
+	}
+	return d
+}
@@ -0,0 +1,232 @@
+// Package app wires together the cache, weather provider chain, and HTTP
+// handlers that serve weather lookups, independent of how the process is
+// invoked (long-running server, one-shot CLI query, or cache warmer).
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/JesterSe7en/Articuno/internal/cache"
+	"github.com/JesterSe7en/Articuno/internal/metrics"
+	"github.com/JesterSe7en/Articuno/internal/weather"
+)
+
+// backgroundRefreshTimeout bounds an asynchronous stale-while-revalidate
+// refresh; it isn't tied to any one request's context since the request
+// that triggered it has already been served a stale response.
+const backgroundRefreshTimeout = 10 * time.Second
+
+// App wires together the cache, provider chain, and in-flight request
+// coalescing that back every HTTP handler and CLI entry point.
+type App struct {
+	rdb   cache.Cache
+	chain *weather.ProviderChain
+	sf    singleflight.Group
+
+	// freshTTL is how long a cache entry is served as-is. Once its age
+	// passes freshTTL but is still under staleTTL, it's served immediately
+	// while a refresh happens in the background (SWR). Past staleTTL the
+	// caller blocks on a synchronous refresh.
+	freshTTL  time.Duration
+	staleTTL  time.Duration
+	jitterPct int
+}
+
+// NewApp builds an App. freshTTL must be <= staleTTL.
+func NewApp(rdb cache.Cache, chain *weather.ProviderChain, freshTTL, staleTTL time.Duration, jitterPct int) *App {
+	return &App{
+		rdb:       rdb,
+		chain:     chain,
+		freshTTL:  freshTTL,
+		staleTTL:  staleTTL,
+		jitterPct: jitterPct,
+	}
+}
+
+// Handler returns the HTTP handler serving the weather UI, SSE stream,
+// admin invalidation endpoint, and Prometheus metrics.
+func (a *App) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", a.rootHandler)
+	mux.HandleFunc("/stream", a.streamHandler)
+	mux.HandleFunc("/admin/invalidate", a.adminInvalidateHandler)
+	mux.Handle("/metrics", promhttp.Handler())
+	return metrics.Middleware(mux)
+}
+
+// CacheEnvelope is what's actually stored under a cache key: the payload
+// plus enough metadata to tell fresh, stale, and expired apart. It's
+// exported so other entry points (e.g. the "warm" CLI command) can write
+// cache entries that GetWeatherData will recognize.
+type CacheEnvelope struct {
+	FetchedAt int64           `json:"fetched_at"`
+	TTL       int64           `json:"ttl"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+func decodeEnvelope(raw string) (CacheEnvelope, error) {
+	var env CacheEnvelope
+	err := json.Unmarshal([]byte(raw), &env)
+	return env, err
+}
+
+// jitteredTTL spreads cache expiry by up to jitterPct% in either direction
+// so entries written around the same time don't all expire together.
+func jitteredTTL(base time.Duration, jitterPct int) time.Duration {
+	if jitterPct <= 0 {
+		return base
+	}
+	spread := float64(base) * float64(jitterPct) / 100
+	jittered := float64(base) + (rand.Float64()*2-1)*spread
+	if jittered < 0 {
+		return 0
+	}
+	return time.Duration(jittered)
+}
+
+func (a *App) rootHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		tmpl, err := template.New("index.html").ParseFiles("index.html") // load the html template
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		tmpl.Execute(w, nil)
+		return
+	}
+
+	weatherData, err := a.GetWeatherData(r.Context(), r.FormValue("city"), r.FormValue("provider"))
+
+	if err != nil {
+		http.Error(w, "City not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/json")
+	w.Write([]byte(weatherData))
+	fmt.Fprintf(w, "City: %s, Weather Data: %s", html.EscapeString(r.FormValue("city")), weatherData)
+}
+
+// GetWeatherData returns the cached report for city, refreshing it from the
+// provider chain when the cache is cold or past its stale ceiling, and
+// kicking off a background refresh when it's stale but still servable. It's
+// exported so CLI one-shot queries can reuse the same cache-aware path the
+// HTTP handler uses.
+func (a *App) GetWeatherData(ctx context.Context, city, preferredProvider string) (string, error) {
+	city = strings.TrimSpace(html.EscapeString(city))
+	if city == "" {
+
+		return "", fmt.Errorf("city cannot be empty")
+	}
+
+	reqID := metrics.RequestIDFromContext(ctx)
+
+	metrics.CityRequests.WithLabelValues(city).Inc()
+	if psp, ok := a.rdb.(cache.PoolStatsProvider); ok {
+		metrics.RecordRedisPoolStats(psp.PoolStats())
+	}
+
+	key := cache.BuildKey(city)
+
+	redisStart := time.Now()
+	raw, err := a.rdb.Get(ctx, key)
+	metrics.RedisLatency.WithLabelValues("get").Observe(time.Since(redisStart).Seconds())
+	if err != nil {
+		log.Printf("request %s: cache get failed for %s: %v\n", reqID, city, err)
+	}
+
+	if raw != "" {
+		if env, err := decodeEnvelope(raw); err == nil {
+			age := time.Since(time.Unix(env.FetchedAt, 0))
+			switch {
+			case age <= a.freshTTL:
+				metrics.CacheHits.Inc()
+				return string(env.Payload), nil
+			case age <= a.staleTTL:
+				metrics.CacheStale.Inc()
+				a.refreshAsync(reqID, city, preferredProvider)
+				return string(env.Payload), nil
+			}
+		}
+	}
+	metrics.CacheMisses.Inc()
+
+	return a.refresh(ctx, reqID, city, preferredProvider)
+}
+
+// refresh fetches city from the provider chain, caches the result under a
+// jittered TTL, and publishes it for /stream subscribers. Concurrent calls
+// for the same city coalesce into a single upstream fetch via singleflight.
+// reqID is the originating request's ID (from metrics.RequestIDFromContext),
+// threaded through purely for log correlation; it plays no part in the
+// singleflight key so coalesced callers still share one upstream fetch.
+func (a *App) refresh(ctx context.Context, reqID, city, preferredProvider string) (string, error) {
+	key := cache.BuildKey(city)
+
+	v, err, _ := a.sf.Do(key, func() (any, error) {
+		report, err := a.chain.Fetch(ctx, city, preferredProvider)
+		if err != nil {
+			return nil, err
+		}
+
+		reportJSON, err := json.Marshal(report)
+		if err != nil {
+			return nil, err
+		}
+
+		envJSON, err := json.Marshal(CacheEnvelope{
+			FetchedAt: time.Now().Unix(),
+			TTL:       int64(a.staleTTL.Seconds()),
+			Payload:   reportJSON,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		setStart := time.Now()
+		setErr := a.rdb.Set(ctx, key, string(envJSON), jitteredTTL(a.staleTTL, a.jitterPct))
+		metrics.RedisLatency.WithLabelValues("set").Observe(time.Since(setStart).Seconds())
+		if setErr != nil {
+			log.Printf("request %s: cache set failed for %s: %v\n", reqID, city, setErr)
+			return nil, setErr
+		}
+
+		// Best-effort: push the fresh report to anyone watching /stream for this city.
+		if pubErr := a.rdb.Publish(ctx, cache.UpdateChannel(city), string(reportJSON)); pubErr != nil {
+			log.Printf("request %s: refresh: failed to publish update for %s: %v\n", reqID, city, pubErr)
+		}
+
+		return string(reportJSON), nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return v.(string), nil
+}
+
+// refreshAsync runs refresh in the background, detached from the request
+// that triggered it, to implement stale-while-revalidate. reqID is carried
+// along only so its log line can still be correlated with the request that
+// triggered the refresh.
+func (a *App) refreshAsync(reqID, city, preferredProvider string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), backgroundRefreshTimeout)
+		defer cancel()
+		if _, err := a.refresh(ctx, reqID, city, preferredProvider); err != nil {
+			log.Printf("request %s: background refresh failed for %s: %v\n", reqID, city, err)
+		}
+	}()
+}
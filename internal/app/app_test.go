@@ -1,28 +1,31 @@
-package main
+package app
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+
+	"github.com/JesterSe7en/Articuno/internal/cache"
+	"github.com/JesterSe7en/Articuno/internal/weather"
 )
 
+var testChain = weather.NewProviderChain([]weather.Provider{weather.NewVisualCrossingProvider(os.Getenv("WEATHER_API_KEY"))}, 5*time.Second, 300*time.Millisecond)
+
 var testRedisClient *redis.Client
-var server = &http.Server{
-	Addr: ":8080",
-}
-var sigChan = make(chan os.Signal, 1)
+var testCache cache.Cache
+var testApp *App
 
 func setup() error {
 
@@ -43,6 +46,17 @@ func setup() error {
 		DB:       0,
 	})
 
+	testCacheClient, err := cache.NewCache(cache.Config{
+		Mode:     cache.ModeSingle,
+		Addrs:    []string{redisURL},
+		Password: redisPassword,
+	})
+	if err != nil {
+		return err
+	}
+	testCache = testCacheClient
+	testApp = NewApp(testCache, testChain, time.Hour, 24*time.Hour, 0)
+
 	// Prepare test data
 	testingData := [...]struct {
 		city        string
@@ -57,7 +71,15 @@ func setup() error {
 	pipe := testRedisClient.Pipeline()
 
 	for _, data := range testingData {
-		pipe.Set(context.Background(), data.city, data.weatherData, 0)
+		envJSON, err := json.Marshal(CacheEnvelope{
+			FetchedAt: time.Now().Unix(),
+			TTL:       int64((24 * time.Hour).Seconds()),
+			Payload:   json.RawMessage(data.weatherData),
+		})
+		if err != nil {
+			return err
+		}
+		pipe.Set(context.Background(), cache.BuildKey(data.city), string(envJSON), 0)
 	}
 	cmds, err := pipe.Exec(context.Background())
 
@@ -71,17 +93,6 @@ func setup() error {
 		}
 	}
 
-	go func() {
-		if err := startWebServer(server, testRedisClient, os.Getenv("WEATHER_API_KEY")); err != nil {
-			log.Fatalf("Failed to start web server: %v", err)
-		}
-	}()
-
-	go func() {
-		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM) // respond to SIGINT(ctrl+c) and SIGTERM (system asks the program to terminate gracefully)
-		<-sigChan                                               // block until a signal is received
-	}()
-
 	return nil
 }
 
@@ -90,16 +101,6 @@ func teardown() {
 		testRedisClient.Close()
 		testRedisClient = nil
 	}
-
-	close(sigChan)
-
-	shutdownCtx, shutdownRelease := context.WithTimeout(context.Background(), 10*time.Second) // 10 seconds wait for graceful shutdown
-	defer shutdownRelease()
-
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("HTTP shutdown error: %v", err)
-	}
-	log.Println("Graceful shutdown complete.")
 }
 
 func TestMain(m *testing.M) {
@@ -127,7 +128,7 @@ func TestGetWeatherData(t *testing.T) {
 
 	for _, testCase := range testingData {
 		// Call the function
-		weatherData, err := getWeatherData(testCase.city, testRedisClient, "")
+		weatherData, err := testApp.GetWeatherData(context.Background(), testCase.city, "")
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
 		}
@@ -158,9 +159,7 @@ func TestRootHandler_Get(t *testing.T) {
 
 		// Create a ResponseRecorder to record the response.
 		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			rootHandler(w, r, nil, os.Getenv("WEATHER_API_KEY")) // Pass nil for Redis in this test
-		})
+		handler := http.HandlerFunc(testApp.rootHandler)
 
 		// Call the handler with the request and response recorder.
 		handler.ServeHTTP(rr, req)
@@ -199,9 +198,7 @@ func TestRootHandler_Post(t *testing.T) {
 
 	// Create a ResponseRecorder to record the response.
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		rootHandler(w, r, nil, os.Getenv("WEATHER_API_KEY")) // Pass nil for Redis in this test
-	})
+	handler := http.HandlerFunc(testApp.rootHandler)
 
 	// Call the handler with the request and response recorder.
 	handler.ServeHTTP(rr, req)
@@ -251,9 +248,7 @@ func TestRootHandler_Post_ValidInputs(t *testing.T) {
 			}
 
 			rr := httptest.NewRecorder()
-			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				rootHandler(w, r, nil, "test_api_key") // Pass nil for Redis in this test
-			})
+			handler := http.HandlerFunc(testApp.rootHandler)
 
 			handler.ServeHTTP(rr, req)
 
@@ -269,3 +264,53 @@ func TestRootHandler_Post_ValidInputs(t *testing.T) {
 		})
 	}
 }
+
+// countingProvider is a fake weather.Provider that counts how many times
+// Fetch is invoked, with an artificial delay so concurrent callers overlap.
+type countingProvider struct {
+	calls int32
+	delay time.Duration
+}
+
+func (p *countingProvider) Name() string { return "counting" }
+
+func (p *countingProvider) Fetch(ctx context.Context, city string) (weather.Report, error) {
+	atomic.AddInt32(&p.calls, 1)
+	time.Sleep(p.delay)
+	return weather.Report{City: city, TemperatureC: 20, Provider: p.Name()}, nil
+}
+
+// TestGetWeatherData_SingleflightCoalescesConcurrentMisses proves that N
+// concurrent cache misses for the same city trigger exactly one upstream
+// fetch, with the rest of the callers coalescing onto it via singleflight.
+func TestGetWeatherData_SingleflightCoalescesConcurrentMisses(t *testing.T) {
+	provider := &countingProvider{delay: 100 * time.Millisecond}
+	chain := weather.NewProviderChain([]weather.Provider{provider}, 5*time.Second, 300*time.Millisecond)
+	a := NewApp(testCache, chain, time.Hour, 24*time.Hour, 0)
+
+	const n = 20
+	city := "Singleflight City"
+	testCache.Del(context.Background(), cache.BuildKey(city))
+
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := a.GetWeatherData(context.Background(), city, "")
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if calls := atomic.LoadInt32(&provider.calls); calls != 1 {
+		t.Errorf("expected exactly 1 upstream fetch, got %d", calls)
+	}
+}
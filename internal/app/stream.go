@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/JesterSe7en/Articuno/internal/cache"
+)
+
+// RunInvalidationSubscriber listens on cache.InvalidateChannel for as long as
+// ctx is alive, deleting the keys it's told to so every instance drops a
+// stale entry together instead of waiting for its TTL.
+func (a *App) RunInvalidationSubscriber(ctx context.Context) {
+	sub, ok := a.rdb.(cache.Subscriber)
+	if !ok {
+		log.Println("invalidation subscriber: cache backend does not support Subscribe, skipping")
+		return
+	}
+
+	pubsub := sub.Subscribe(ctx, cache.InvalidateChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := a.rdb.Del(ctx, msg.Payload); err != nil {
+				log.Println("invalidation subscriber: failed to delete key:", err)
+			}
+		}
+	}
+}
+
+// adminInvalidateHandler publishes an invalidation message for a city so
+// every instance subscribed via RunInvalidationSubscriber drops it from
+// their cache. It requires an Authorization: Bearer <ADMIN_TOKEN> header
+// matching the ADMIN_TOKEN environment variable.
+func (a *App) adminInvalidateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	if adminToken == "" || r.Header.Get("Authorization") != "Bearer "+adminToken {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	city := r.FormValue("city")
+	if city == "" {
+		http.Error(w, "city is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := a.rdb.Publish(r.Context(), cache.InvalidateChannel, cache.BuildKey(city)); err != nil {
+		http.Error(w, "failed to publish invalidation", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// streamHandler serves GET /stream?city=..., a Server-Sent Events endpoint
+// that pushes every fresh report GetWeatherData publishes for that city.
+func (a *App) streamHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	city := r.FormValue("city")
+	if city == "" {
+		http.Error(w, "city is required", http.StatusBadRequest)
+		return
+	}
+
+	sub, ok := a.rdb.(cache.Subscriber)
+	if !ok {
+		http.Error(w, "streaming is not supported by this cache backend", http.StatusNotImplemented)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := r.Context()
+	pubsub := sub.Subscribe(ctx, cache.UpdateChannel(city))
+	defer pubsub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", msg.Payload)
+			flusher.Flush()
+		}
+	}
+}
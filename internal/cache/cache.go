@@ -0,0 +1,195 @@
+// Package cache abstracts the Redis backend used to store weather reports so
+// that the rest of Articuno can talk to a single node, a Sentinel-managed
+// failover group, or a Redis Cluster without caring which one it got.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache is the minimal surface getWeatherData needs from a Redis backend.
+// Keeping it small means tests can fake it instead of standing up a real
+// *redis.Client.
+type Cache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+	Publish(ctx context.Context, channel string, message string) error
+
+	// SetMany writes every entry in one pipelined round trip, the same ttl
+	// applied to each key. It's meant for bulk population (e.g. cache
+	// warming), where a Set-per-key would mean a network round trip per key.
+	SetMany(ctx context.Context, entries map[string]string, ttl time.Duration) error
+}
+
+// PoolStatsProvider is implemented by backends that can report connection
+// pool health; callers use a type assertion to opt in (mirrors http.Flusher).
+type PoolStatsProvider interface {
+	PoolStats() *redis.PoolStats
+}
+
+// Subscriber is implemented by backends that can open a Pub/Sub connection;
+// callers use a type assertion to opt in, since Subscribe needs a dedicated
+// connection rather than a single command.
+type Subscriber interface {
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
+// Mode selects which Redis deployment topology to connect to.
+type Mode string
+
+const (
+	ModeSingle   Mode = "single"
+	ModeSentinel Mode = "sentinel"
+	ModeCluster  Mode = "cluster"
+)
+
+// Config describes how to reach the Redis backend, independent of topology.
+type Config struct {
+	Mode Mode
+
+	// Addrs is one "host:port" for ModeSingle, the sentinel addresses for
+	// ModeSentinel, or the seed nodes for ModeCluster.
+	Addrs []string
+
+	// MasterName is required for ModeSentinel; it names the monitored master.
+	MasterName string
+
+	Password string
+	DB       int
+
+	// ReadyRetries is how many times to retry the startup Ping before
+	// NewCache gives up. Defaults to 5 if zero.
+	ReadyRetries int
+	// ReadyBackoff is the base delay between Ping retries, doubled each
+	// attempt. Defaults to 200ms if zero.
+	ReadyBackoff time.Duration
+}
+
+func (c Config) readyRetries() int {
+	if c.ReadyRetries > 0 {
+		return c.ReadyRetries
+	}
+	return 5
+}
+
+func (c Config) readyBackoff() time.Duration {
+	if c.ReadyBackoff > 0 {
+		return c.ReadyBackoff
+	}
+	return 200 * time.Millisecond
+}
+
+// redisCache adapts any redis.Cmdable (Client, ClusterClient, or the Client
+// returned by NewFailoverClient) to the Cache interface.
+type redisCache struct {
+	cmdable redis.Cmdable
+}
+
+func (r *redisCache) Get(ctx context.Context, key string) (string, error) {
+	val, err := r.cmdable.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+func (r *redisCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return r.cmdable.Set(ctx, key, value, ttl).Err()
+}
+
+func (r *redisCache) Del(ctx context.Context, key string) error {
+	return r.cmdable.Del(ctx, key).Err()
+}
+
+func (r *redisCache) SetMany(ctx context.Context, entries map[string]string, ttl time.Duration) error {
+	pipe := r.cmdable.Pipeline()
+	for key, value := range entries {
+		pipe.Set(ctx, key, value, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *redisCache) Publish(ctx context.Context, channel string, message string) error {
+	return r.cmdable.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe is exposed when the underlying client supports it; both
+// *redis.Client and *redis.ClusterClient do.
+func (r *redisCache) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	if s, ok := r.cmdable.(Subscriber); ok {
+		return s.Subscribe(ctx, channels...)
+	}
+	return nil
+}
+
+// PoolStats is exposed when the underlying client supports it; both
+// *redis.Client and *redis.ClusterClient do.
+func (r *redisCache) PoolStats() *redis.PoolStats {
+	if p, ok := r.cmdable.(interface{ PoolStats() *redis.PoolStats }); ok {
+		return p.PoolStats()
+	}
+	return nil
+}
+
+// NewCache builds a Cache for the topology named by cfg.Mode, waiting for the
+// backend to become reachable before returning.
+func NewCache(cfg Config) (Cache, error) {
+	if len(cfg.Addrs) == 0 {
+		return nil, fmt.Errorf("cache: at least one address is required")
+	}
+
+	var cmdable redis.Cmdable
+	switch cfg.Mode {
+	case "", ModeSingle:
+		cmdable = redis.NewClient(&redis.Options{
+			Addr:     cfg.Addrs[0],
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		})
+	case ModeSentinel:
+		if cfg.MasterName == "" {
+			return nil, fmt.Errorf("cache: REDIS_MASTER_NAME is required in sentinel mode")
+		}
+		cmdable = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    cfg.MasterName,
+			SentinelAddrs: cfg.Addrs,
+			Password:      cfg.Password,
+			DB:            cfg.DB,
+		})
+	case ModeCluster:
+		cmdable = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    cfg.Addrs,
+			Password: cfg.Password,
+		})
+	default:
+		return nil, fmt.Errorf("cache: unknown REDIS_MODE %q", cfg.Mode)
+	}
+
+	if err := waitUntilReady(cmdable, cfg.readyRetries(), cfg.readyBackoff()); err != nil {
+		return nil, err
+	}
+
+	return &redisCache{cmdable: cmdable}, nil
+}
+
+// waitUntilReady pings the backend, retrying with exponential backoff, until
+// it responds or retries are exhausted.
+func waitUntilReady(cmdable redis.Cmdable, retries int, backoff time.Duration) error {
+	var lastErr error
+	for attempt := 0; attempt < retries; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		lastErr = cmdable.Ping(ctx).Err()
+		cancel()
+		if lastErr == nil {
+			return nil
+		}
+		time.Sleep(backoff * time.Duration(1<<attempt))
+	}
+	return fmt.Errorf("cache: backend not ready after %d attempts: %w", retries, lastErr)
+}
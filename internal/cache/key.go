@@ -0,0 +1,28 @@
+package cache
+
+import "strings"
+
+// InvalidateChannel is the Pub/Sub channel a cache entry can be evicted on
+// from any instance; the message payload is the cache key to delete.
+const InvalidateChannel = "weather.invalidate"
+
+// normalizeCity lowercases and collapses whitespace so that "New York" and
+// "  new   york  " resolve to the same identifier.
+func normalizeCity(city string) string {
+	normalized := strings.ToLower(strings.TrimSpace(city))
+	return strings.Join(strings.Fields(normalized), "-")
+}
+
+// BuildKey normalizes a city name into a stable cache key. Wrapping the city
+// in a hash tag ("{...}") keeps the key on a single Redis Cluster hash slot
+// regardless of any prefix added around it.
+func BuildKey(city string) string {
+	return "weather:{" + normalizeCity(city) + "}"
+}
+
+// UpdateChannel is the per-city Pub/Sub channel a fresh report is published
+// on after a successful upstream refresh; /stream subscribes here to push
+// live updates to browsers.
+func UpdateChannel(city string) string {
+	return "weather.update." + normalizeCity(city)
+}
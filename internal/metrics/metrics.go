@@ -0,0 +1,124 @@
+// Package metrics exposes Prometheus-style counters, histograms, and gauges
+// for the weather API, along with an HTTP middleware that logs each request
+// as structured JSON and tags it with a request ID for correlation.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// CacheHits/CacheMisses count Redis lookups performed by getWeatherData.
+	CacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "articuno_cache_hits_total",
+		Help: "Number of weather lookups served from the Redis cache.",
+	})
+	CacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "articuno_cache_misses_total",
+		Help: "Number of weather lookups that missed the Redis cache.",
+	})
+	// CacheStale counts lookups served from a stale-but-not-expired cache
+	// entry while a background refresh is in flight (stale-while-revalidate).
+	CacheStale = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "articuno_cache_stale_served_total",
+		Help: "Number of weather lookups served from a stale cache entry pending background refresh.",
+	})
+
+	// CityRequests counts requests per city so hot cities are easy to spot.
+	CityRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "articuno_city_requests_total",
+		Help: "Number of weather requests, labeled by requested city.",
+	}, []string{"city"})
+
+	// UpstreamStatus counts upstream weather provider responses, labeled by
+	// provider and status class.
+	UpstreamStatus = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "articuno_upstream_responses_total",
+		Help: "Number of upstream weather provider responses, labeled by provider and status class (2xx/4xx/5xx/error).",
+	}, []string{"provider", "class"})
+
+	// UpstreamLatency tracks how long each weather provider call takes.
+	UpstreamLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "articuno_upstream_latency_seconds",
+		Help:    "Latency of calls to upstream weather providers, labeled by provider.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider"})
+
+	// HandlerLatency tracks total time spent in rootHandler.
+	HandlerLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "articuno_handler_latency_seconds",
+		Help:    "Latency of HTTP handlers, labeled by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	// RedisLatency tracks GET/SET latency against Redis.
+	RedisLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "articuno_redis_latency_seconds",
+		Help:    "Latency of Redis commands issued by the weather handler, labeled by op.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"op"})
+
+	// InFlightRequests is the number of HTTP requests currently being handled.
+	InFlightRequests = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "articuno_in_flight_requests",
+		Help: "Number of HTTP requests currently being served.",
+	})
+
+	// Redis connection pool gauges, refreshed from rdb.PoolStats().
+	redisPoolHits = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "articuno_redis_pool_hits",
+		Help: "Number of times a free connection was found in the Redis pool.",
+	})
+	redisPoolMisses = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "articuno_redis_pool_misses",
+		Help: "Number of times a free connection was not found in the Redis pool.",
+	})
+	redisPoolTimeouts = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "articuno_redis_pool_timeouts",
+		Help: "Number of times a wait timeout occurred acquiring a Redis connection.",
+	})
+	redisPoolTotalConns = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "articuno_redis_pool_total_conns",
+		Help: "Total number of connections currently open in the Redis pool.",
+	})
+	redisPoolIdleConns = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "articuno_redis_pool_idle_conns",
+		Help: "Number of idle connections currently in the Redis pool.",
+	})
+	redisPoolStaleConns = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "articuno_redis_pool_stale_conns",
+		Help: "Number of stale connections removed from the Redis pool.",
+	})
+)
+
+// UpstreamStatusClass maps an HTTP status code to the label used by
+// UpstreamStatus, e.g. 200 -> "2xx".
+func UpstreamStatusClass(statusCode int) string {
+	switch {
+	case statusCode >= 200 && statusCode < 300:
+		return "2xx"
+	case statusCode >= 400 && statusCode < 500:
+		return "4xx"
+	case statusCode >= 500:
+		return "5xx"
+	default:
+		return "other"
+	}
+}
+
+// RecordRedisPoolStats copies the live stats from a go-redis pool into the
+// corresponding gauges. Callers are expected to invoke this periodically
+// (e.g. once per request) since go-redis doesn't push pool stats itself.
+func RecordRedisPoolStats(stats *redis.PoolStats) {
+	if stats == nil {
+		return
+	}
+	redisPoolHits.Set(float64(stats.Hits))
+	redisPoolMisses.Set(float64(stats.Misses))
+	redisPoolTimeouts.Set(float64(stats.Timeouts))
+	redisPoolTotalConns.Set(float64(stats.TotalConns))
+	redisPoolIdleConns.Set(float64(stats.IdleConns))
+	redisPoolStaleConns.Set(float64(stats.StaleConns))
+}
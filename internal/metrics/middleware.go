@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+type requestIDKey struct{}
+
+// logFormat controls how Middleware emits its access log line. It's meant to
+// be set once at startup (e.g. from a --log-format flag) before the server
+// starts accepting requests.
+var logFormat = "json"
+
+// SetLogFormat selects how Middleware logs requests: "json" (the default)
+// for structured lines, or "text" for a human-readable one. It returns an
+// error if format is neither.
+func SetLogFormat(format string) error {
+	switch format {
+	case "json", "text":
+		logFormat = format
+		return nil
+	default:
+		return fmt.Errorf("unknown log format %q, want \"json\" or \"text\"", format)
+	}
+}
+
+// WithRequestID returns a copy of ctx carrying the given request ID, so it
+// can be threaded down into cache/backend calls for correlation.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stashed on ctx, or "" if none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return fmt.Sprintf("%x", b)
+}
+
+// statusRecorder wraps a ResponseWriter so the middleware can observe the
+// status code a handler actually wrote.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+type requestLogLine struct {
+	RequestID  string `json:"request_id"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Status     int    `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Middleware wraps next with structured JSON access logging, a per-request
+// ID propagated via context.Context, and the InFlightRequests/HandlerLatency
+// instrumentation.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		InFlightRequests.Inc()
+		defer InFlightRequests.Dec()
+
+		start := time.Now()
+
+		reqID := r.Header.Get("X-Request-Id")
+		if reqID == "" {
+			reqID = newRequestID()
+		}
+		ctx := WithRequestID(r.Context(), reqID)
+		r = r.WithContext(ctx)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		duration := time.Since(start)
+		HandlerLatency.WithLabelValues(r.URL.Path, r.Method).Observe(duration.Seconds())
+
+		if logFormat == "text" {
+			log.Printf("%s %s %s %d %dms\n", reqID, r.Method, r.URL.Path, rec.status, duration.Milliseconds())
+			return
+		}
+
+		line, err := json.Marshal(requestLogLine{
+			RequestID:  reqID,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Status:     rec.status,
+			DurationMS: duration.Milliseconds(),
+		})
+		if err != nil {
+			log.Println("metrics: failed to marshal request log line:", err)
+			return
+		}
+		log.Println(string(line))
+	})
+}
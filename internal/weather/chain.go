@@ -0,0 +1,177 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JesterSe7en/Articuno/internal/metrics"
+)
+
+// chainEntry pairs a Provider with the circuit breaker tracking its health.
+type chainEntry struct {
+	provider Provider
+	breaker  *CircuitBreaker
+}
+
+// ProviderChain tries a set of providers in order, skipping any whose
+// circuit is open, and hedges a slow primary by racing it against the next
+// available provider after hedgeDelay.
+type ProviderChain struct {
+	entries            []*chainEntry
+	perProviderTimeout time.Duration
+	hedgeDelay         time.Duration
+}
+
+// NewProviderChain builds a chain over providers, in priority order.
+func NewProviderChain(providers []Provider, perProviderTimeout, hedgeDelay time.Duration) *ProviderChain {
+	entries := make([]*chainEntry, len(providers))
+	for i, p := range providers {
+		// Trip a provider after 5+ requests with a 50% failure rate, and
+		// give it 30s to recover before probing it again.
+		entries[i] = &chainEntry{provider: p, breaker: NewCircuitBreaker(0.5, 5, 30*time.Second)}
+	}
+	return &ProviderChain{entries: entries, perProviderTimeout: perProviderTimeout, hedgeDelay: hedgeDelay}
+}
+
+// Fetch returns a Report for city, trying providers in order (starting with
+// preferred, if it names one in the chain) and hedging slow primaries.
+func (c *ProviderChain) Fetch(ctx context.Context, city, preferred string) (Report, error) {
+	entries := c.orderedEntries(preferred)
+
+	var lastErr error
+	for i := 0; i < len(entries); {
+		primary := entries[i]
+		if !primary.breaker.Allow() {
+			lastErr = fmt.Errorf("%s: circuit open", primary.provider.Name())
+			i++
+			continue
+		}
+
+		hedgeIdx := -1
+		for j := i + 1; j < len(entries); j++ {
+			if entries[j].breaker.Allow() {
+				hedgeIdx = j
+				break
+			}
+		}
+
+		report, usedIdx, hedgeFired, err := c.race(ctx, city, entries, i, hedgeIdx)
+		if err == nil {
+			entries[usedIdx].breaker.RecordSuccess()
+			return report, nil
+		}
+		lastErr = err
+
+		if hedgeFired {
+			// The hedge provider was already tried (and failed) as part of
+			// this race, so skip past it instead of trying it again.
+			i = hedgeIdx + 1
+		} else {
+			i++
+		}
+	}
+
+	return Report{}, fmt.Errorf("weather: all providers failed: %w", lastErr)
+}
+
+type fetchOutcome struct {
+	idx    int
+	report Report
+	err    error
+}
+
+// race fetches from entries[primaryIdx] and, if it hasn't returned within
+// c.hedgeDelay, also fetches from entries[hedgeIdx] (when >= 0), returning
+// whichever succeeds first. The returned bool reports whether the hedge
+// provider was actually started, so a caller whose primary fails before the
+// hedge ever fires knows not to treat hedgeIdx as tried.
+func (c *ProviderChain) race(ctx context.Context, city string, entries []*chainEntry, primaryIdx, hedgeIdx int) (Report, int, bool, error) {
+	outcomes := make(chan fetchOutcome, 2)
+	go func() {
+		report, err := c.fetchOne(ctx, entries[primaryIdx].provider, city)
+		outcomes <- fetchOutcome{idx: primaryIdx, report: report, err: err}
+	}()
+
+	pending := 1
+	hedgeFired := hedgeIdx < 0
+
+	timer := time.NewTimer(c.hedgeDelay)
+	defer timer.Stop()
+
+	errs := make(map[int]error, 2)
+	for pending > 0 {
+		select {
+		case out := <-outcomes:
+			pending--
+			if out.err == nil {
+				return out.report, out.idx, hedgeFired && hedgeIdx >= 0, nil
+			}
+			errs[out.idx] = out.err
+			entries[out.idx].breaker.RecordFailure()
+			if out.idx == primaryIdx && !hedgeFired {
+				// Primary failed before the hedge delay elapsed: stop
+				// waiting on the timer and report the failure now so the
+				// caller can try hedgeIdx itself rather than skipping it.
+				return Report{}, 0, false, fmt.Errorf("%s: %w", entries[primaryIdx].provider.Name(), errs[primaryIdx])
+			}
+		case <-timer.C:
+			if hedgeFired {
+				continue
+			}
+			hedgeFired = true
+			pending++
+			go func() {
+				report, err := c.fetchOne(ctx, entries[hedgeIdx].provider, city)
+				outcomes <- fetchOutcome{idx: hedgeIdx, report: report, err: err}
+			}()
+		}
+	}
+
+	return Report{}, 0, hedgeFired && hedgeIdx >= 0, fmt.Errorf("%s: %w", entries[primaryIdx].provider.Name(), errs[primaryIdx])
+}
+
+// fetchOne runs a single provider fetch under c.perProviderTimeout,
+// recording upstream latency/status metrics along the way.
+func (c *ProviderChain) fetchOne(ctx context.Context, p Provider, city string) (Report, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.perProviderTimeout)
+	defer cancel()
+
+	start := time.Now()
+	report, err := p.Fetch(ctx, city)
+	metrics.UpstreamLatency.WithLabelValues(p.Name()).Observe(time.Since(start).Seconds())
+	if err != nil {
+		class := "error"
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			class = metrics.UpstreamStatusClass(statusErr.StatusCode)
+		}
+		metrics.UpstreamStatus.WithLabelValues(p.Name(), class).Inc()
+		return Report{}, err
+	}
+	metrics.UpstreamStatus.WithLabelValues(p.Name(), "2xx").Inc()
+	return report, nil
+}
+
+// orderedEntries returns the chain's entries with the one named by preferred
+// moved to the front, if it exists; otherwise the chain's default order.
+func (c *ProviderChain) orderedEntries(preferred string) []*chainEntry {
+	if preferred == "" {
+		return c.entries
+	}
+
+	ordered := make([]*chainEntry, 0, len(c.entries))
+	var picked *chainEntry
+	for _, e := range c.entries {
+		if e.provider.Name() == preferred {
+			picked = e
+			continue
+		}
+		ordered = append(ordered, e)
+	}
+	if picked == nil {
+		return c.entries
+	}
+	return append([]*chainEntry{picked}, ordered...)
+}
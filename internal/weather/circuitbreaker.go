@@ -0,0 +1,119 @@
+package weather
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitState is the state of a CircuitBreaker.
+type circuitState int
+
+const (
+	stateClosed circuitState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+// CircuitBreaker trips a provider "open" once its rolling error rate crosses
+// a threshold, so the chain stops wasting hedge slots and timeouts on a
+// provider that's already down. After a cooldown it lets one probe request
+// through (half-open); success closes it again, failure re-opens it.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	state    circuitState
+	total    int
+	failures int
+	openedAt time.Time
+
+	errorRateThreshold float64
+	minSamples         int
+	windowSize         int
+	cooldown           time.Duration
+}
+
+// NewCircuitBreaker trips once at least minSamples requests have been seen
+// and the failure rate reaches errorRateThreshold, staying open for cooldown
+// before allowing a half-open probe. The error rate is computed over a
+// rolling window of roughly 4x minSamples requests, so old successes and
+// failures age out instead of diluting (or inflating) the rate forever.
+func NewCircuitBreaker(errorRateThreshold float64, minSamples int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		errorRateThreshold: errorRateThreshold,
+		minSamples:         minSamples,
+		windowSize:         minSamples * 4,
+		cooldown:           cooldown,
+	}
+}
+
+// Allow reports whether a request should be attempted right now.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == stateOpen {
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = stateHalfOpen
+	}
+	return true
+}
+
+// RecordSuccess reports a successful request, closing the breaker if it was
+// half-open, and counting toward the rolling error rate otherwise.
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == stateHalfOpen {
+		b.resetLocked()
+		return
+	}
+	b.recordLocked(false)
+}
+
+// RecordFailure reports a failed request, tripping the breaker open if the
+// half-open probe failed or the rolling error rate crosses the threshold.
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == stateHalfOpen {
+		b.tripLocked()
+		return
+	}
+	b.recordLocked(true)
+}
+
+// recordLocked folds a success or failure into the rolling window and trips
+// the breaker if the error rate over that window crosses the threshold.
+func (b *CircuitBreaker) recordLocked(failed bool) {
+	b.total++
+	if failed {
+		b.failures++
+	}
+	if b.total >= b.minSamples && float64(b.failures)/float64(b.total) >= b.errorRateThreshold {
+		b.tripLocked()
+		return
+	}
+	if b.total >= b.windowSize {
+		// Halve both counters to age out older samples while preserving
+		// the current error rate, keeping the window "rolling" instead of
+		// accumulating forever.
+		b.total /= 2
+		b.failures /= 2
+	}
+}
+
+func (b *CircuitBreaker) tripLocked() {
+	b.state = stateOpen
+	b.openedAt = time.Now()
+	b.total = 0
+	b.failures = 0
+}
+
+func (b *CircuitBreaker) resetLocked() {
+	b.state = stateClosed
+	b.total = 0
+	b.failures = 0
+}
@@ -0,0 +1,62 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// OpenMeteoProvider fetches reports from Open-Meteo, which needs no API key
+// but requires resolving the city to coordinates via its geocoding API
+// first.
+type OpenMeteoProvider struct {
+	HTTPClient *http.Client
+}
+
+// NewOpenMeteoProvider builds an OpenMeteoProvider.
+func NewOpenMeteoProvider() *OpenMeteoProvider {
+	return &OpenMeteoProvider{HTTPClient: http.DefaultClient}
+}
+
+func (p *OpenMeteoProvider) Name() string { return "open-meteo" }
+
+func (p *OpenMeteoProvider) Fetch(ctx context.Context, city string) (Report, error) {
+	geocodeURL := fmt.Sprintf("https://geocoding-api.open-meteo.com/v1/search?count=1&name=%s", url.QueryEscape(city))
+
+	var geocodeResult struct {
+		Results []struct {
+			Name      string  `json:"name"`
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"results"`
+	}
+	if err := getJSON(ctx, p.HTTPClient, p.Name(), geocodeURL, &geocodeResult); err != nil {
+		return Report{}, err
+	}
+	if len(geocodeResult.Results) == 0 {
+		return Report{}, fmt.Errorf("%s: city not found", p.Name())
+	}
+	location := geocodeResult.Results[0]
+
+	forecastURL := fmt.Sprintf(
+		"https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&current_weather=true",
+		location.Latitude, location.Longitude,
+	)
+	var forecast struct {
+		CurrentWeather struct {
+			Temperature float64 `json:"temperature"`
+			WeatherCode int     `json:"weathercode"`
+		} `json:"current_weather"`
+	}
+	if err := getJSON(ctx, p.HTTPClient, p.Name(), forecastURL, &forecast); err != nil {
+		return Report{}, err
+	}
+
+	return Report{
+		City:         location.Name,
+		TemperatureC: forecast.CurrentWeather.Temperature,
+		Conditions:   fmt.Sprintf("wmo code %d", forecast.CurrentWeather.WeatherCode),
+		Provider:     p.Name(),
+	}, nil
+}
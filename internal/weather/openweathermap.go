@@ -0,0 +1,52 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// OpenWeatherMapProvider fetches reports from the OpenWeatherMap current
+// weather API.
+type OpenWeatherMapProvider struct {
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// NewOpenWeatherMapProvider builds an OpenWeatherMapProvider using apiKey.
+func NewOpenWeatherMapProvider(apiKey string) *OpenWeatherMapProvider {
+	return &OpenWeatherMapProvider{APIKey: apiKey, HTTPClient: http.DefaultClient}
+}
+
+func (p *OpenWeatherMapProvider) Name() string { return "openweathermap" }
+
+func (p *OpenWeatherMapProvider) Fetch(ctx context.Context, city string) (Report, error) {
+	requestURL := fmt.Sprintf(
+		"https://api.openweathermap.org/data/2.5/weather?q=%s&units=metric&appid=%s",
+		url.QueryEscape(city), p.APIKey,
+	)
+
+	var payload struct {
+		Name string `json:"name"`
+		Main struct {
+			Temp float64 `json:"temp"`
+		} `json:"main"`
+		Weather []struct {
+			Description string `json:"description"`
+		} `json:"weather"`
+	}
+	if err := getJSON(ctx, p.HTTPClient, p.Name(), requestURL, &payload); err != nil {
+		return Report{}, err
+	}
+	if len(payload.Weather) == 0 {
+		return Report{}, fmt.Errorf("%s: no weather conditions returned", p.Name())
+	}
+
+	return Report{
+		City:         payload.Name,
+		TemperatureC: payload.Main.Temp,
+		Conditions:   payload.Weather[0].Description,
+		Provider:     p.Name(),
+	}, nil
+}
@@ -0,0 +1,66 @@
+// Package weather abstracts over upstream weather APIs so Articuno can query
+// more than one provider, fail over between them, and cache a single
+// provider-agnostic schema instead of whatever shape each API happens to
+// return.
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Report is the normalized shape every Provider returns, regardless of which
+// upstream API produced it. This is also what gets cached, so cache entries
+// stay meaningful even if the provider that filled them changes.
+type Report struct {
+	City         string  `json:"city"`
+	TemperatureC float64 `json:"temperature_c"`
+	Conditions   string  `json:"conditions,omitempty"`
+	Provider     string  `json:"provider"`
+}
+
+// Provider fetches a weather report for city from a single upstream API.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context, city string) (Report, error)
+}
+
+// StatusError reports a non-200 response from an upstream provider. It
+// carries the HTTP status code so callers (the provider chain's metrics)
+// can distinguish a 4xx from a 5xx instead of collapsing every failure into
+// an undifferentiated error.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: request failed with status code: %d", e.Provider, e.StatusCode)
+}
+
+// getJSON issues a GET request against requestURL and decodes the JSON body
+// into out, returning an error tagged with providerName on any failure. A
+// non-200 response is returned as a *StatusError.
+func getJSON(ctx context.Context, client *http.Client, providerName, requestURL string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", providerName, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", providerName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{Provider: providerName, StatusCode: resp.StatusCode}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s: %w", providerName, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// VisualCrossingProvider fetches reports from the VisualCrossing Timeline
+// API, the original (and default) backend for Articuno.
+type VisualCrossingProvider struct {
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// NewVisualCrossingProvider builds a VisualCrossingProvider using apiKey.
+func NewVisualCrossingProvider(apiKey string) *VisualCrossingProvider {
+	return &VisualCrossingProvider{APIKey: apiKey, HTTPClient: http.DefaultClient}
+}
+
+func (p *VisualCrossingProvider) Name() string { return "visualcrossing" }
+
+func (p *VisualCrossingProvider) Fetch(ctx context.Context, city string) (Report, error) {
+	requestURL := fmt.Sprintf(
+		"https://weather.visualcrossing.com/VisualCrossingWebServices/rest/services/timeline/%s?unitGroup=metric&key=%s",
+		url.QueryEscape(city), p.APIKey,
+	)
+
+	var payload struct {
+		ResolvedAddress string `json:"resolvedAddress"`
+		Days            []struct {
+			Temp       float64 `json:"temp"`
+			Conditions string  `json:"conditions"`
+		} `json:"days"`
+	}
+	if err := getJSON(ctx, p.HTTPClient, p.Name(), requestURL, &payload); err != nil {
+		return Report{}, err
+	}
+	if len(payload.Days) == 0 {
+		return Report{}, fmt.Errorf("%s: no forecast days returned", p.Name())
+	}
+
+	return Report{
+		City:         payload.ResolvedAddress,
+		TemperatureC: payload.Days[0].Temp,
+		Conditions:   payload.Days[0].Conditions,
+		Provider:     p.Name(),
+	}, nil
+}